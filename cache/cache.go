@@ -0,0 +1,159 @@
+// Package cache provides an on-disk cache of per-file git status and
+// metadata, backed by bbolt, so repeated `lah -g` invocations in large
+// repos don't have to re-walk an unchanged worktree. It mirrors the
+// approach tools like treefmt use to skip reprocessing unchanged files.
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+var entriesBucket = []byte("entries")
+
+// Entry is the cached state for a single file, keyed by its absolute path.
+type Entry struct {
+	Size        int64     `json:"size"`
+	ModTime     time.Time `json:"mtime"`
+	Mode        uint32    `json:"mode"`
+	GitStatus   string    `json:"git_status"`
+	GitHeadHash string    `json:"git_head_hash"`
+}
+
+// Cache wraps a bbolt database at $XDG_CACHE_HOME/lah/cache.db. Put stages
+// writes in memory and Close flushes them in a single transaction, so
+// listing a large repo costs one fsync instead of one per file.
+type Cache struct {
+	db      *bbolt.DB
+	mu      sync.Mutex
+	pending map[string]Entry
+}
+
+// Path returns the on-disk location of the cache database.
+func Path() string {
+	return filepath.Join(cacheDir(), "lah", "cache.db")
+}
+
+// Open opens (creating if necessary) the cache database.
+func Open() (*Cache, error) {
+	path := Path()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(entriesBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Cache{db: db, pending: make(map[string]Entry)}, nil
+}
+
+// Close flushes any pending writes in a single transaction, then closes the
+// underlying database.
+func (c *Cache) Close() error {
+	if err := c.Flush(); err != nil {
+		c.db.Close()
+		return err
+	}
+	return c.db.Close()
+}
+
+// Flush writes every entry staged by Put since the last Flush in one bbolt
+// transaction.
+func (c *Cache) Flush() error {
+	c.mu.Lock()
+	pending := c.pending
+	c.pending = make(map[string]Entry)
+	c.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	return c.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(entriesBucket)
+		for absPath, entry := range pending {
+			data, err := json.Marshal(entry)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put([]byte(absPath), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Get returns the cached entry for absPath, if any, checking entries staged
+// by Put but not yet flushed before falling back to the database.
+func (c *Cache) Get(absPath string) (Entry, bool) {
+	c.mu.Lock()
+	if entry, ok := c.pending[absPath]; ok {
+		c.mu.Unlock()
+		return entry, true
+	}
+	c.mu.Unlock()
+
+	var entry Entry
+	found := false
+
+	_ = c.db.View(func(tx *bbolt.Tx) error {
+		data := tx.Bucket(entriesBucket).Get([]byte(absPath))
+		if data == nil {
+			return nil
+		}
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil
+		}
+		found = true
+		return nil
+	})
+
+	return entry, found
+}
+
+// Put stages entry for absPath, overwriting any previous value, to be
+// written out by the next Flush (or Close).
+func (c *Cache) Put(absPath string, entry Entry) error {
+	c.mu.Lock()
+	c.pending[absPath] = entry
+	c.mu.Unlock()
+	return nil
+}
+
+// Clear removes every cached entry.
+func Clear() error {
+	path := Path()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	return os.Remove(path)
+}
+
+// cacheDir returns $XDG_CACHE_HOME, falling back to ~/.cache.
+func cacheDir() string {
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".cache")
+}
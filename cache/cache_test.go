@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPutGetRoundTrip(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	c, err := Open()
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer c.Close()
+
+	want := Entry{
+		Size:        1234,
+		ModTime:     time.Now().Truncate(time.Second),
+		Mode:        0644,
+		GitStatus:   "+1 -0",
+		GitHeadHash: "deadbeef",
+	}
+
+	if err := c.Put("/tmp/example.go", want); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	got, ok := c.Get("/tmp/example.go")
+	if !ok {
+		t.Fatal("expected a cache hit after Put")
+	}
+	if got.Size != want.Size || got.Mode != want.Mode || got.GitStatus != want.GitStatus ||
+		got.GitHeadHash != want.GitHeadHash || !got.ModTime.Equal(want.ModTime) {
+		t.Errorf("Get = %+v, want %+v", got, want)
+	}
+
+	if _, ok := c.Get("/tmp/missing.go"); ok {
+		t.Error("expected a cache miss for a key that was never Put")
+	}
+}
+
+func TestClearRemovesDatabase(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	c, err := Open()
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if err := c.Put("/tmp/example.go", Entry{Size: 1}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	c.Close()
+
+	if err := Clear(); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+
+	c2, err := Open()
+	if err != nil {
+		t.Fatalf("Open after Clear: %v", err)
+	}
+	defer c2.Close()
+
+	if _, ok := c2.Get("/tmp/example.go"); ok {
+		t.Error("expected no entries after Clear")
+	}
+}
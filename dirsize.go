@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+)
+
+// DirSizeMode selects what --dir-size shows in place of a directory's usual
+// "-" Size cell.
+type DirSizeMode string
+
+const (
+	DirSizeNone  DirSizeMode = ""
+	DirSizeCount DirSizeMode = "count"
+	DirSizeBytes DirSizeMode = "bytes"
+	DirSizeBoth  DirSizeMode = "both"
+)
+
+func (m DirSizeMode) enabled() bool {
+	return m != DirSizeNone
+}
+
+func (m DirSizeMode) String() string {
+	return string(m)
+}
+
+// Set implements pflag.Value so DirSizeMode can be bound directly to the
+// --dir-size flag with validation.
+func (m *DirSizeMode) Set(value string) error {
+	switch DirSizeMode(value) {
+	case DirSizeCount, DirSizeBytes, DirSizeBoth:
+		*m = DirSizeMode(value)
+		return nil
+	default:
+		return fmt.Errorf("invalid --dir-size %q (want one of: count, bytes, both)", value)
+	}
+}
+
+// Type implements pflag.Value.
+func (m DirSizeMode) Type() string {
+	return "string"
+}
+
+// computeDirSizes populates EntryCount and/or Size for every directory entry
+// in files, per config.DirSize, using a worker pool bounded by GOMAXPROCS so
+// sizing many large directories doesn't run unbounded goroutines.
+func computeDirSizes(files []FileInfo, config Config) {
+	if !config.DirSize.enabled() {
+		return
+	}
+
+	stop := startSizingSpinner()
+	defer stop()
+
+	sem := make(chan struct{}, max(runtime.GOMAXPROCS(0), 1))
+	var wg sync.WaitGroup
+
+	for i := range files {
+		if !files[i].IsDir {
+			continue
+		}
+
+		includeBytes := config.DirSize == DirSizeBytes || config.DirSize == DirSizeBoth
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			count, total := sizeDir(files[i].Path, config.FollowSymlinks, includeBytes)
+			files[i].EntryCount = count
+			if includeBytes {
+				files[i].Size = total
+			}
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+// sizeDir returns the number of immediate entries in dir and, when
+// includeBytes is set, the recursive byte total of everything under it.
+// Count-only callers (DirSizeCount) skip the recursive walk entirely, since
+// a single ReadDir is all that's needed. Symlinked directories are only
+// descended into when followSymlinks is set; a visited-paths guard still
+// applies in that case to avoid infinite recursion on a symlink cycle.
+func sizeDir(dir string, followSymlinks, includeBytes bool) (count int64, total int64) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, 0
+	}
+	count = int64(len(entries))
+
+	if !includeBytes {
+		return count, 0
+	}
+
+	visited := map[string]bool{}
+	if real, err := filepath.EvalSymlinks(dir); err == nil {
+		visited[real] = true
+	}
+
+	_, total = sizeEntries(dir, entries, followSymlinks, visited)
+	return count, total
+}
+
+// sizeEntries recurses into dir's subdirectories to accumulate the total
+// byte size, tracking visited (symlink-resolved) real paths so a cycle like
+// a/link -> .. terminates instead of recursing forever.
+func sizeEntries(dir string, entries []os.DirEntry, followSymlinks bool, visited map[string]bool) (count int64, total int64) {
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+
+		if info.Mode()&fs.ModeSymlink != 0 {
+			if !followSymlinks {
+				continue
+			}
+			target, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			if target.IsDir() {
+				total += sizeVisitedDir(path, followSymlinks, visited)
+			} else {
+				total += target.Size()
+			}
+			continue
+		}
+
+		if entry.IsDir() {
+			total += sizeVisitedDir(path, followSymlinks, visited)
+		} else {
+			total += info.Size()
+		}
+	}
+
+	return count, total
+}
+
+// sizeVisitedDir resolves dir's real path and, unless it's already been
+// visited in this walk, recurses into it and marks it visited.
+func sizeVisitedDir(dir string, followSymlinks bool, visited map[string]bool) int64 {
+	if real, err := filepath.EvalSymlinks(dir); err == nil {
+		if visited[real] {
+			return 0
+		}
+		visited[real] = true
+	}
+
+	subEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+
+	_, total := sizeEntries(dir, subEntries, followSymlinks, visited)
+	return total
+}
+
+// formatDirSize renders a directory's Size cell according to config.DirSize,
+// falling back to formatSize's plain "-" when --dir-size wasn't requested.
+func formatDirSize(file FileInfo, config Config) string {
+	if !file.IsDir || !config.DirSize.enabled() {
+		return formatSize(file.Size, file.IsDir)
+	}
+
+	switch config.DirSize {
+	case DirSizeCount:
+		return color.New(color.FgCyan).Sprintf("%d", file.EntryCount)
+	case DirSizeBytes:
+		return color.New(color.FgCyan).Sprint(humanSize(file.Size))
+	case DirSizeBoth:
+		return color.New(color.FgCyan).Sprintf("%d / %s", file.EntryCount, humanSize(file.Size))
+	default:
+		return formatSize(file.Size, file.IsDir)
+	}
+}
+
+// startSizingSpinner shows a "calculating..." spinner on stderr once sizing
+// has been running for 300ms, but only when stdout is a terminal (so piped
+// output stays clean). It returns a func to stop and clear the spinner.
+func startSizingSpinner() func() {
+	if !isatty.IsTerminal(os.Stdout.Fd()) {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-time.After(300 * time.Millisecond):
+		case <-done:
+			return
+		}
+
+		frames := []rune{'|', '/', '-', '\\'}
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				fmt.Fprint(os.Stderr, "\r\x1b[K")
+				return
+			case <-ticker.C:
+				fmt.Fprintf(os.Stderr, "\r%c calculating directory sizes...", frames[i%len(frames)])
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDirSizeModeSet(t *testing.T) {
+	var m DirSizeMode
+	for _, value := range []string{"count", "bytes", "both"} {
+		if err := m.Set(value); err != nil {
+			t.Errorf("Set(%q) returned an error: %v", value, err)
+		}
+	}
+
+	if err := m.Set("huge"); err == nil {
+		t.Error("expected an error for an unsupported --dir-size value")
+	}
+}
+
+func TestSizeDir(t *testing.T) {
+	root := t.TempDir()
+
+	mustWrite := func(rel string, content string) {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("MkdirAll(%q): %v", rel, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("WriteFile(%q): %v", rel, err)
+		}
+	}
+
+	mustWrite("a.txt", "hello")
+	mustWrite("sub/b.txt", "world!")
+
+	count, total := sizeDir(root, false, true)
+	if count != 2 {
+		t.Errorf("count = %d, want 2 (a.txt, sub)", count)
+	}
+	if want := int64(len("hello") + len("world!")); total != want {
+		t.Errorf("total = %d, want %d", total, want)
+	}
+}
+
+func TestSizeDirCountOnlySkipsRecursion(t *testing.T) {
+	root := t.TempDir()
+
+	mustWrite := func(rel string, content string) {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("MkdirAll(%q): %v", rel, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("WriteFile(%q): %v", rel, err)
+		}
+	}
+
+	mustWrite("a.txt", "hello")
+	mustWrite("sub/b.txt", "world!")
+
+	count, total := sizeDir(root, false, false)
+	if count != 2 {
+		t.Errorf("count = %d, want 2 (a.txt, sub)", count)
+	}
+	if total != 0 {
+		t.Errorf("total = %d, want 0 when includeBytes is false", total)
+	}
+}
+
+func TestSizeDirFollowsSymlinkCycleWithoutOverflow(t *testing.T) {
+	root := t.TempDir()
+
+	sub := filepath.Join(root, "a")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(sub, "f.txt"), []byte("hi"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	link := filepath.Join(sub, "loop")
+	if err := os.Symlink(root, link); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		sizeDir(root, true, true)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("sizeDir did not terminate on a symlink cycle")
+	}
+}
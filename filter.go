@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// timeLayouts is the fallback parsing order for --after/--before once a
+// value has been ruled out as a Unix timestamp or a duration.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	time.DateTime,
+	time.DateOnly,
+}
+
+// parseTimeish parses the permissive --after/--before value format: a Unix
+// timestamp (seconds, milliseconds, or microseconds, detected by digit
+// count), a duration offset from now (e.g. "-24h", "72h"), or one of
+// timeLayouts.
+func parseTimeish(value string, now time.Time) (time.Time, error) {
+	if t, ok := parseUnixTimestamp(value); ok {
+		return t, nil
+	}
+
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(d), nil
+	}
+
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("could not parse %q as a timestamp, duration, or date", value)
+}
+
+// parseUnixTimestamp recognizes a plain digit string as a Unix timestamp,
+// inferring the unit from its length: 10 digits = seconds, 13 = milliseconds,
+// 16 = microseconds.
+func parseUnixTimestamp(value string) (time.Time, bool) {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	switch len(value) {
+	case 10:
+		return time.Unix(n, 0), true
+	case 13:
+		return time.UnixMilli(n), true
+	case 16:
+		return time.UnixMicro(n), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// filterByTimeRange keeps only entries whose ModTime falls within [after, before],
+// either bound being zero-valued to mean "unbounded". It returns the kept
+// entries and the count of entries hidden by the filter.
+func filterByTimeRange(files []FileInfo, after, before time.Time) ([]FileInfo, int) {
+	if after.IsZero() && before.IsZero() {
+		return files, 0
+	}
+
+	kept := files[:0:0]
+	hidden := 0
+	for _, f := range files {
+		if !after.IsZero() && f.ModTime.Before(after) {
+			hidden++
+			continue
+		}
+		if !before.IsZero() && f.ModTime.After(before) {
+			hidden++
+			continue
+		}
+		kept = append(kept, f)
+	}
+
+	return kept, hidden
+}
+
+// applyFilters applies --after/--before and --top/--bottom to an
+// already-sorted slice of files, returning the kept entries and the total
+// number of entries hidden by either filter.
+func applyFilters(files []FileInfo, config Config, now time.Time) ([]FileInfo, int, error) {
+	var after, before time.Time
+	var err error
+
+	if config.After != "" {
+		if after, err = parseTimeish(config.After, now); err != nil {
+			return nil, 0, fmt.Errorf("--after: %w", err)
+		}
+	}
+	if config.Before != "" {
+		if before, err = parseTimeish(config.Before, now); err != nil {
+			return nil, 0, fmt.Errorf("--before: %w", err)
+		}
+	}
+
+	files, hiddenByTime := filterByTimeRange(files, after, before)
+	files, hiddenByTopBottom, err := topBottom(files, config.Top, config.Bottom)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return files, hiddenByTime + hiddenByTopBottom, nil
+}
+
+// printFilterSummary prints a small summary line noting how many entries
+// were hidden by --after/--before/--top/--bottom, if any.
+func printFilterSummary(hidden int) {
+	if hidden == 0 {
+		return
+	}
+	fmt.Println(color.New(color.FgHiBlack).Sprintf("(%d entries hidden by filter)", hidden))
+}
+
+// topBottom keeps only the n entries with the newest (top) or oldest
+// (bottom) ModTime, regardless of the table's current sort order, returning
+// the kept entries (in their original relative order) and how many were
+// trimmed. n <= 0 disables the cap; --top and --bottom may not both be set.
+func topBottom(files []FileInfo, top, bottom int) ([]FileInfo, int, error) {
+	if top > 0 && bottom > 0 {
+		return nil, 0, fmt.Errorf("--top and --bottom cannot be used together")
+	}
+
+	n, newest := top, true
+	if bottom > 0 {
+		n, newest = bottom, false
+	}
+	if n <= 0 || n >= len(files) {
+		return files, 0, nil
+	}
+
+	indices := make([]int, len(files))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		a, b := files[indices[i]].ModTime, files[indices[j]].ModTime
+		if newest {
+			return a.After(b)
+		}
+		return a.Before(b)
+	})
+
+	keep := make(map[int]bool, n)
+	for _, idx := range indices[:n] {
+		keep[idx] = true
+	}
+
+	kept := files[:0:0]
+	for i, f := range files {
+		if keep[i] {
+			kept = append(kept, f)
+		}
+	}
+
+	return kept, len(files) - len(kept), nil
+}
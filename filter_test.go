@@ -0,0 +1,90 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeish(t *testing.T) {
+	now := time.Date(2026, 7, 26, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		value string
+		want  time.Time
+	}{
+		{"unix seconds", "1700000000", time.Unix(1700000000, 0)},
+		{"unix milliseconds", "1700000000000", time.UnixMilli(1700000000000)},
+		{"unix microseconds", "1700000000000000", time.UnixMicro(1700000000000000)},
+		{"negative duration", "-24h", now.Add(-24 * time.Hour)},
+		{"positive duration", "72h", now.Add(72 * time.Hour)},
+		{"date only", "2026-01-02", time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTimeish(tt.value, now)
+			if err != nil {
+				t.Fatalf("parseTimeish(%q) error: %v", tt.value, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseTimeish(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTimeishInvalid(t *testing.T) {
+	if _, err := parseTimeish("not-a-time", time.Now()); err == nil {
+		t.Error("expected an error for an unparseable value")
+	}
+}
+
+func TestFilterByTimeRange(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	files := []FileInfo{
+		{Name: "old", ModTime: base.Add(-48 * time.Hour)},
+		{Name: "mid", ModTime: base.Add(-12 * time.Hour)},
+		{Name: "new", ModTime: base.Add(12 * time.Hour)},
+	}
+
+	kept, hidden := filterByTimeRange(files, base.Add(-24*time.Hour), time.Time{})
+	if hidden != 1 {
+		t.Errorf("expected 1 hidden, got %d", hidden)
+	}
+	if len(kept) != 2 || kept[0].Name != "mid" || kept[1].Name != "new" {
+		t.Errorf("unexpected kept entries: %+v", kept)
+	}
+}
+
+func TestTopBottom(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	// Deliberately out of ModTime order to prove selection keys off ModTime,
+	// not position in the slice.
+	files := []FileInfo{
+		{Name: "a", ModTime: base.Add(1 * time.Hour)},
+		{Name: "b", ModTime: base.Add(3 * time.Hour)},
+		{Name: "c", ModTime: base},
+		{Name: "d", ModTime: base.Add(2 * time.Hour)},
+	}
+
+	top, trimmed, err := topBottom(files, 2, 0)
+	if err != nil {
+		t.Fatalf("top(2): %v", err)
+	}
+	if trimmed != 2 || len(top) != 2 || top[0].Name != "b" || top[1].Name != "d" {
+		t.Errorf("top(2) = %+v, trimmed=%d, want [b d] (the 2 newest, in original order)", top, trimmed)
+	}
+
+	bottom, trimmed, err := topBottom(files, 0, 1)
+	if err != nil {
+		t.Fatalf("bottom(1): %v", err)
+	}
+	if trimmed != 3 || len(bottom) != 1 || bottom[0].Name != "c" {
+		t.Errorf("bottom(1) = %+v, trimmed=%d, want [c] (the oldest)", bottom, trimmed)
+	}
+
+	if _, _, err := topBottom(files, 1, 1); err == nil {
+		t.Error("expected an error when --top and --bottom are both set")
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/go-git/go-git/v5"
+
+	"github.com/ipanardian/lah/cache"
+)
+
+// repoState holds the lazily-computed git state for one repository root:
+// its HEAD hash and, once needed, a single Status() scan reused for every
+// file in that repo for the lifetime of this invocation.
+type repoState struct {
+	worktree     *git.Worktree
+	head         string
+	status       git.Status
+	statusLoaded bool
+}
+
+// gitStatusCache batches git lookups per invocation so that listing N files
+// in the same repository costs one worktree.Status() call, not N.
+type gitStatusCache struct {
+	repos map[string]*repoState
+}
+
+func newGitStatusCache() *gitStatusCache {
+	return &gitStatusCache{repos: map[string]*repoState{}}
+}
+
+// repoFor resolves the repository containing dir (searching upward for
+// .git, like `git status` would), returning its cached repoState keyed by
+// worktree root.
+func (c *gitStatusCache) repoFor(dir string) (*repoState, string, bool) {
+	repo, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, "", false
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, "", false
+	}
+
+	root := worktree.Filesystem.Root()
+	if state, ok := c.repos[root]; ok {
+		return state, root, true
+	}
+
+	state := &repoState{worktree: worktree}
+	if head, err := repo.Head(); err == nil {
+		state.head = head.Hash().String()
+	}
+	c.repos[root] = state
+
+	return state, root, true
+}
+
+// headHash returns the HEAD commit hash of the repository containing dir,
+// or "" if dir isn't inside a git repository.
+func (c *gitStatusCache) headHash(dir string) string {
+	state, _, ok := c.repoFor(dir)
+	if !ok {
+		return ""
+	}
+	return state.head
+}
+
+// statusFor returns the (lazily loaded) full worktree status for the
+// repository containing dir, along with the repo root to resolve relative
+// paths against.
+func (c *gitStatusCache) statusFor(dir string) (git.Status, string, bool) {
+	state, root, ok := c.repoFor(dir)
+	if !ok {
+		return nil, "", false
+	}
+
+	if !state.statusLoaded {
+		status, err := state.worktree.Status()
+		if err != nil {
+			return nil, "", false
+		}
+		state.status = status
+		state.statusLoaded = true
+	}
+
+	return state.status, root, true
+}
+
+// prepareGitCaches sets up the in-memory batched status cache (always, when
+// -g is set) and the on-disk cache (unless --no-cache was passed). It
+// returns nil, nil when config.ShowGit is false.
+func prepareGitCaches(config Config) (*gitStatusCache, *cache.Cache) {
+	if !config.ShowGit {
+		return nil, nil
+	}
+
+	gsCache := newGitStatusCache()
+	if config.NoCache {
+		return gsCache, nil
+	}
+
+	diskCache, err := cache.Open()
+	if err != nil {
+		return gsCache, nil
+	}
+
+	return gsCache, diskCache
+}
+
+// getGitStatusCached returns file's git status summary, batched and
+// optionally disk-cached: it consults diskCache (when non-nil) before falling
+// back to a single worktree.Status() per repo, and records the result back
+// to disk.
+func getGitStatusCached(file FileInfo, diskCache *cache.Cache, gsCache *gitStatusCache) string {
+	dir := filepath.Dir(file.Path)
+	head := gsCache.headHash(dir)
+
+	absPath, err := filepath.Abs(file.Path)
+	if err != nil {
+		absPath = file.Path
+	}
+
+	if diskCache != nil && head != "" {
+		if entry, ok := diskCache.Get(absPath); ok {
+			if entry.Size == file.Size && entry.ModTime.Equal(file.ModTime) && entry.GitHeadHash == head {
+				return entry.GitStatus
+			}
+		}
+	}
+
+	status := gitStatusFromCache(file.Path, gsCache)
+
+	if diskCache != nil {
+		_ = diskCache.Put(absPath, cache.Entry{
+			Size:        file.Size,
+			ModTime:     file.ModTime,
+			Mode:        uint32(file.Mode),
+			GitStatus:   status,
+			GitHeadHash: head,
+		})
+	}
+
+	return status
+}
+
+// gitStatusFromCache computes a file's "+added -deleted"/"(clean)" summary
+// against a gitStatusCache's batched Status() call instead of scanning the
+// repo again for every file.
+func gitStatusFromCache(filePath string, gsCache *gitStatusCache) string {
+	dir := filepath.Dir(filePath)
+
+	status, root, ok := gsCache.statusFor(dir)
+	if !ok {
+		return ""
+	}
+
+	relPath, err := filepath.Rel(root, filePath)
+	if err != nil {
+		return ""
+	}
+
+	fileStatus := status.File(relPath)
+	if fileStatus.Worktree == git.Unmodified && fileStatus.Staging == git.Unmodified {
+		return "(clean)"
+	}
+
+	var added, deleted int
+	if fileStatus.Worktree == git.Added || fileStatus.Staging == git.Added {
+		added++
+	}
+	if fileStatus.Worktree == git.Deleted || fileStatus.Staging == git.Deleted {
+		deleted++
+	}
+	if fileStatus.Worktree == git.Modified || fileStatus.Staging == git.Modified {
+		added++
+	}
+
+	if added > 0 || deleted > 0 {
+		return fmt.Sprintf("+%d -%d", added, deleted)
+	}
+
+	return ""
+}
@@ -0,0 +1,135 @@
+package icons
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/fatih/color"
+)
+
+// Colors holds SGR (ANSI Select Graphic Rendition) codes keyed by the same
+// GNU ls codes LS_COLORS uses (di, ln, ex, so, pi, bd, cd) plus "*.ext"
+// extension overrides, e.g. "01;34" for bold blue.
+type Colors struct {
+	ByType map[FileType]string
+	ByExt  map[string]string
+}
+
+var lsColorTypeKeys = map[string]FileType{
+	"di": TypeDir,
+	"ln": TypeSymlink,
+	"ex": TypeExecutable,
+	"so": TypeSocket,
+	"pi": TypeFifo,
+	"bd": TypeDevice,
+	"cd": TypeDevice,
+}
+
+// LoadColors builds a Colors table from the LS_COLORS or LSCOLORS
+// environment variable (GNU and BSD ls formats respectively, GNU taking
+// precedence when both are set), then applies any overrides from
+// $XDG_CONFIG_HOME/lah/colors.toml.
+func LoadColors() *Colors {
+	c := &Colors{ByType: map[FileType]string{}, ByExt: map[string]string{}}
+
+	if gnu := os.Getenv("LS_COLORS"); gnu != "" {
+		parseGNULSColors(gnu, c)
+	} else if bsd := os.Getenv("LSCOLORS"); bsd != "" {
+		parseBSDLSColors(bsd, c)
+	}
+
+	path := filepath.Join(configDir(), "lah", "colors.toml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c
+	}
+
+	var cfg struct {
+		Types      map[string]string `toml:"types"`
+		Extensions map[string]string `toml:"extensions"`
+	}
+	if _, err := toml.Decode(string(data), &cfg); err != nil {
+		return c
+	}
+	for typeName, sgr := range cfg.Types {
+		if ft, ok := typeNames[typeName]; ok {
+			c.ByType[ft] = sgr
+		}
+	}
+	for ext, sgr := range cfg.Extensions {
+		c.ByExt[strings.ToLower(ext)] = sgr
+	}
+
+	return c
+}
+
+// parseGNULSColors parses the standard "di=01;34:ln=01;36:*.go=32:..." format.
+func parseGNULSColors(spec string, c *Colors) {
+	for _, entry := range strings.Split(spec, ":") {
+		key, sgr, ok := strings.Cut(entry, "=")
+		if !ok || sgr == "" {
+			continue
+		}
+
+		if ft, ok := lsColorTypeKeys[key]; ok {
+			c.ByType[ft] = sgr
+			continue
+		}
+		if strings.HasPrefix(key, "*.") {
+			c.ByExt[strings.ToLower(key[1:])] = sgr
+		}
+	}
+}
+
+// bsdLetterToSGR maps a single BSD LSCOLORS foreground letter to its GNU-ish
+// SGR equivalent; 'x' means "use the terminal default" (no override).
+var bsdLetterToSGR = map[byte]string{
+	'a': "30", 'b': "31", 'c': "32", 'd': "33",
+	'e': "34", 'f': "35", 'g': "36", 'h': "37",
+	'A': "1;30", 'B': "1;31", 'C': "1;32", 'D': "1;33",
+	'E': "1;34", 'F': "1;35", 'G': "1;36", 'H': "1;37",
+}
+
+// bsdOrder is the fixed slot order of the 11 BSD LSCOLORS file types.
+var bsdOrder = []FileType{
+	TypeDir, TypeSymlink, TypeSocket, TypeFifo, TypeExecutable,
+	TypeDevice, TypeDevice, TypeExecutable, TypeExecutable, TypeDir, TypeDir,
+}
+
+// parseBSDLSColors parses the legacy 22-character LSCOLORS string: a
+// foreground/background letter pair per file type, in bsdOrder.
+func parseBSDLSColors(spec string, c *Colors) {
+	for i, ft := range bsdOrder {
+		pos := i * 2
+		if pos+1 >= len(spec) {
+			break
+		}
+		fg := spec[pos]
+		if sgr, ok := bsdLetterToSGR[fg]; ok {
+			c.ByType[ft] = sgr
+		}
+	}
+}
+
+// ColorFor resolves the SGR code for name, trying its extension first (GNU
+// ls lets extension rules override the generic "file" bucket), falling
+// back to the file-type code.
+func (c *Colors) ColorFor(name string, ft FileType) string {
+	if ext := strings.ToLower(filepath.Ext(name)); ext != "" {
+		if sgr, ok := c.ByExt[ext]; ok {
+			return sgr
+		}
+	}
+	return c.ByType[ft]
+}
+
+// Colorize wraps s in sgr's ANSI escape sequence, or returns s unchanged if
+// sgr is empty or color.NoColor is set (e.g. stdout isn't a terminal).
+func Colorize(s, sgr string) string {
+	if sgr == "" || color.NoColor {
+		return s
+	}
+	return "\x1b[" + sgr + "m" + s + "\x1b[0m"
+}
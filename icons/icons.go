@@ -0,0 +1,166 @@
+// Package icons maps files to nerd-font glyphs and LS_COLORS-driven colors,
+// the way the lf file manager's icons.go/colors.go do: a built-in default
+// table that a user config can extend or override.
+package icons
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// FileType is a coarse fallback bucket used when neither an exact filename
+// nor an extension matches.
+type FileType int
+
+const (
+	TypeRegular FileType = iota
+	TypeDir
+	TypeSymlink
+	TypeExecutable
+	TypeSocket
+	TypeFifo
+	TypeDevice
+)
+
+// Map holds the three lookup tiers, checked in priority order: exact
+// filename, extension, then file-type fallback.
+type Map struct {
+	ByName map[string]string
+	ByExt  map[string]string
+	ByType map[FileType]string
+}
+
+// defaultIcons is the built-in glyph table. Glyphs are Nerd Font codepoints;
+// they render as ordinary tofu/boxes without a patched font installed.
+func defaultIcons() *Map {
+	return &Map{
+		ByName: map[string]string{
+			"Dockerfile":         "",
+			"Makefile":           "",
+			"go.mod":             "",
+			"go.sum":             "",
+			".gitignore":         "",
+			"LICENSE":            "",
+			"README.md":          "",
+			"package.json":       "",
+			"docker-compose.yml": "",
+		},
+		ByExt: map[string]string{
+			".go":   "",
+			".rs":   "",
+			".py":   "",
+			".js":   "",
+			".ts":   "",
+			".jsx":  "",
+			".tsx":  "",
+			".md":   "",
+			".txt":  "",
+			".rst":  "",
+			".yml":  "",
+			".yaml": "",
+			".json": "",
+			".toml": "",
+			".ini":  "",
+			".sh":   "",
+			".zip":  "",
+			".tar":  "",
+			".gz":   "",
+			".png":  "",
+			".jpg":  "",
+			".jpeg": "",
+			".gif":  "",
+			".svg":  "",
+			".pdf":  "",
+		},
+		ByType: map[FileType]string{
+			TypeDir:        "",
+			TypeSymlink:    "",
+			TypeExecutable: "",
+			TypeSocket:     "",
+			TypeFifo:       "",
+			TypeDevice:     "",
+			TypeRegular:    "",
+		},
+	}
+}
+
+// iconsConfig mirrors the shape of icons.toml: flat name/ext/type tables
+// that get merged over the built-in defaults.
+type iconsConfig struct {
+	Names     map[string]string `toml:"names"`
+	Extension map[string]string `toml:"extensions"`
+	Types     map[string]string `toml:"types"`
+}
+
+var typeNames = map[string]FileType{
+	"dir":        TypeDir,
+	"symlink":    TypeSymlink,
+	"executable": TypeExecutable,
+	"socket":     TypeSocket,
+	"fifo":       TypeFifo,
+	"device":     TypeDevice,
+	"regular":    TypeRegular,
+}
+
+// Load returns the default icon map merged with any overrides found at
+// $XDG_CONFIG_HOME/lah/icons.toml.
+func Load() *Map {
+	m := defaultIcons()
+
+	path := filepath.Join(configDir(), "lah", "icons.toml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return m
+	}
+
+	var cfg iconsConfig
+	if _, err := toml.Decode(string(data), &cfg); err != nil {
+		return m
+	}
+
+	for name, glyph := range cfg.Names {
+		m.ByName[name] = glyph
+	}
+	for ext, glyph := range cfg.Extension {
+		m.ByExt[strings.ToLower(ext)] = glyph
+	}
+	for typeName, glyph := range cfg.Types {
+		if ft, ok := typeNames[typeName]; ok {
+			m.ByType[ft] = glyph
+		}
+	}
+
+	return m
+}
+
+// Lookup resolves the glyph for name, checked in priority order: exact
+// filename, extension, then the file-type fallback.
+func (m *Map) Lookup(name string, ft FileType) string {
+	if glyph, ok := m.ByName[name]; ok {
+		return glyph
+	}
+	if ext := strings.ToLower(filepath.Ext(name)); ext != "" {
+		if glyph, ok := m.ByExt[ext]; ok {
+			return glyph
+		}
+	}
+	if glyph, ok := m.ByType[ft]; ok {
+		return glyph
+	}
+	return m.ByType[TypeRegular]
+}
+
+// configDir returns $XDG_CONFIG_HOME, falling back to ~/.config.
+func configDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config")
+}
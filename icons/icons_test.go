@@ -0,0 +1,77 @@
+package icons
+
+import (
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestMapLookupPriority(t *testing.T) {
+	m := defaultIcons()
+	m.ByName["custom"] = "N"
+	m.ByExt[".custom"] = "E"
+	m.ByType[TypeRegular] = "R"
+
+	if got := m.Lookup("custom", TypeRegular); got != "N" {
+		t.Errorf("exact name should win, got %q", got)
+	}
+	if got := m.Lookup("file.custom", TypeRegular); got != "E" {
+		t.Errorf("extension should be used when no name match, got %q", got)
+	}
+	if got := m.Lookup("file.unknown-ext", TypeRegular); got != "R" {
+		t.Errorf("type fallback should be used when nothing else matches, got %q", got)
+	}
+}
+
+func TestParseGNULSColors(t *testing.T) {
+	c := &Colors{ByType: map[FileType]string{}, ByExt: map[string]string{}}
+	parseGNULSColors("di=01;34:ln=01;36:*.go=01;32", c)
+
+	if c.ByType[TypeDir] != "01;34" {
+		t.Errorf("di = %q, want 01;34", c.ByType[TypeDir])
+	}
+	if c.ByType[TypeSymlink] != "01;36" {
+		t.Errorf("ln = %q, want 01;36", c.ByType[TypeSymlink])
+	}
+	if c.ByExt[".go"] != "01;32" {
+		t.Errorf("*.go = %q, want 01;32", c.ByExt[".go"])
+	}
+}
+
+func TestColorForPrefersExtensionOverType(t *testing.T) {
+	c := &Colors{
+		ByType: map[FileType]string{TypeRegular: "37"},
+		ByExt:  map[string]string{".go": "32"},
+	}
+
+	if got := c.ColorFor("main.go", TypeRegular); got != "32" {
+		t.Errorf("ColorFor(main.go) = %q, want 32", got)
+	}
+	if got := c.ColorFor("README", TypeRegular); got != "37" {
+		t.Errorf("ColorFor(README) = %q, want 37", got)
+	}
+}
+
+func TestColorize(t *testing.T) {
+	if got := Colorize("name", ""); got != "name" {
+		t.Errorf("Colorize with empty sgr should be a no-op, got %q", got)
+	}
+
+	old := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = old }()
+
+	if got := Colorize("name", "01;32"); got != "\x1b[01;32mname\x1b[0m" {
+		t.Errorf("Colorize(name, 01;32) = %q", got)
+	}
+}
+
+func TestColorizeNoopWhenNoColor(t *testing.T) {
+	old := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = old }()
+
+	if got := Colorize("name", "01;32"); got != "name" {
+		t.Errorf("Colorize should leave name unchanged when color.NoColor is set, got %q", got)
+	}
+}
@@ -18,35 +18,58 @@ import (
 	"strconv"
 	"strings"
 	"time"
-	"unicode/utf8"
 
 	"github.com/fatih/color"
-	"github.com/go-git/go-git/v5"
+	"github.com/mattn/go-runewidth"
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
 
 	"github.com/ipanardian/lah/box/table"
+	"github.com/ipanardian/lah/cache"
+	"github.com/ipanardian/lah/icons"
 )
 
 type FileInfo struct {
-	Name      string
-	Path      string
-	Size      int64
-	Mode      fs.FileMode
-	ModTime   time.Time
-	IsDir     bool
-	IsHidden  bool
-	GitStatus string
+	Name       string
+	Path       string
+	Size       int64
+	EntryCount int64
+	Mode       fs.FileMode
+	ModTime    time.Time
+	IsDir      bool
+	IsHidden   bool
+	GitStatus  string
 }
 
 type Config struct {
-	SortModified bool
-	Reverse      bool
-	ShowGit      bool
+	SortModified     bool
+	Reverse          bool
+	ShowGit          bool
+	Recursive        bool
+	Depth            int
+	RespectGitignore bool
+	Tree             bool
+	After            string
+	Before           string
+	Top              int
+	Bottom           int
+	Icons            bool
+	IconMap          *icons.Map
+	ColorMap         *icons.Colors
+	NoCache          bool
+	GitCache         *gitStatusCache
+	DiskCache        *cache.Cache
+	Output           OutputFormat
+	DirSize          DirSizeMode
+	FollowSymlinks   bool
+	Root             string
 }
 
 func main() {
-	var config Config
+	config := Config{
+		IconMap:  icons.Load(),
+		ColorMap: icons.LoadColors(),
+	}
 
 	var rootCmd = &cobra.Command{
 		Use:   "lah [path]",
@@ -63,6 +86,8 @@ Version: v1.0.0`,
 				path = args[0]
 			}
 
+			disableColorForOutput(config.Output)
+
 			if err := listDirectory(path, config); err != nil {
 				log.Fatalf("Error: %v", err)
 			}
@@ -72,6 +97,33 @@ Version: v1.0.0`,
 	rootCmd.Flags().BoolVarP(&config.SortModified, "sort-modified", "t", false, "sort by modified time (newest first)")
 	rootCmd.Flags().BoolVarP(&config.Reverse, "reverse", "r", false, "reverse sort order")
 	rootCmd.Flags().BoolVarP(&config.ShowGit, "git", "g", false, "show git status inline")
+	rootCmd.Flags().BoolVarP(&config.Recursive, "recursive", "R", false, "recursively list subdirectories")
+	rootCmd.Flags().IntVarP(&config.Depth, "depth", "d", 0, "max recursion depth for -R (0 = unlimited)")
+	rootCmd.Flags().BoolVar(&config.RespectGitignore, "respect-gitignore", true, "skip paths matched by .gitignore when recursing")
+	rootCmd.Flags().BoolVar(&config.Tree, "tree", false, "render -R output as an indented tree instead of a flat table")
+	rootCmd.Flags().StringVar(&config.After, "after", "", "only show entries modified after this time (unix timestamp, duration like -24h, or date)")
+	rootCmd.Flags().StringVar(&config.Before, "before", "", "only show entries modified before this time (unix timestamp, duration, or date)")
+	rootCmd.Flags().IntVar(&config.Top, "top", 0, "keep only the N newest matching entries after sorting")
+	rootCmd.Flags().IntVar(&config.Bottom, "bottom", 0, "keep only the N oldest matching entries after sorting")
+	rootCmd.Flags().BoolVarP(&config.Icons, "icons", "i", false, "show a nerd-font icon before each name")
+	rootCmd.Flags().BoolVar(&config.NoCache, "no-cache", false, "don't read or write the on-disk git status cache")
+	rootCmd.Flags().VarP(&config.Output, "output", "o", "output format: table, json, ndjson, tar")
+	rootCmd.Flags().Var(&config.DirSize, "dir-size", "show directory entry counts and/or recursive sizes: count, bytes, both")
+	rootCmd.Flags().BoolVarP(&config.FollowSymlinks, "dereference", "L", false, "follow symlinked directories when computing --dir-size")
+
+	cacheCmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage lah's on-disk cache",
+	}
+	cacheCmd.AddCommand(&cobra.Command{
+		Use:   "clear",
+		Short: "Remove the cached git status and metadata database",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cache.Clear()
+		},
+	})
+	rootCmd.AddCommand(cacheCmd)
 	rootCmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
 		showColoredHelp(cmd)
 	})
@@ -82,11 +134,20 @@ Version: v1.0.0`,
 }
 
 func listDirectory(path string, config Config) error {
+	if config.Recursive {
+		return listDirectoryRecursive(path, config)
+	}
+
 	entries, err := os.ReadDir(path)
 	if err != nil {
 		return err
 	}
 
+	config.GitCache, config.DiskCache = prepareGitCaches(config)
+	if config.DiskCache != nil {
+		defer config.DiskCache.Close()
+	}
+
 	var files []FileInfo
 	now := time.Now()
 
@@ -107,7 +168,7 @@ func listDirectory(path string, config Config) error {
 		}
 
 		if config.ShowGit {
-			fileInfo.GitStatus = getGitStatus(fileInfo.Path)
+			fileInfo.GitStatus = getGitStatusCached(fileInfo, config.DiskCache, config.GitCache)
 		}
 
 		files = append(files, fileInfo)
@@ -115,7 +176,20 @@ func listDirectory(path string, config Config) error {
 
 	sortFiles(files, config)
 
-	printTable(files, now, config)
+	files, hidden, err := applyFilters(files, config, now)
+	if err != nil {
+		return err
+	}
+
+	computeDirSizes(files, config)
+
+	config.Root = path
+	if err := renderStructured(files, now, config); err != nil {
+		return err
+	}
+	if config.Output.IsTable() {
+		printFilterSummary(hidden)
+	}
 
 	return nil
 }
@@ -143,51 +217,6 @@ func sortFiles(files []FileInfo, config Config) {
 	}
 }
 
-func getGitStatus(filePath string) string {
-	dir := filepath.Dir(filePath)
-	repo, err := git.PlainOpen(dir)
-	if err != nil {
-		return ""
-	}
-
-	worktree, err := repo.Worktree()
-	if err != nil {
-		return ""
-	}
-
-	relPath, err := filepath.Rel(dir, filePath)
-	if err != nil {
-		return ""
-	}
-
-	status, err := worktree.Status()
-	if err != nil {
-		return ""
-	}
-
-	fileStatus := status.File(relPath)
-	if fileStatus.Worktree == git.Unmodified && fileStatus.Staging == git.Unmodified {
-		return "(clean)"
-	}
-
-	var added, deleted int
-	if fileStatus.Worktree == git.Added || fileStatus.Staging == git.Added {
-		added++
-	}
-	if fileStatus.Worktree == git.Deleted || fileStatus.Staging == git.Deleted {
-		deleted++
-	}
-	if fileStatus.Worktree == git.Modified || fileStatus.Staging == git.Modified {
-		added++
-	}
-
-	if added > 0 || deleted > 0 {
-		return fmt.Sprintf("+%d -%d", added, deleted)
-	}
-
-	return ""
-}
-
 func getTerminalWidth() int {
 	if width := os.Getenv("COLUMNS"); width != "" {
 		if w, err := strconv.Atoi(width); err == nil && w > 0 {
@@ -237,8 +266,6 @@ func printTable(files []FileInfo, now time.Time, config Config) {
 		return
 	}
 
-	terminalWidth := max(getTerminalWidth(), 40)
-
 	data := make([][]string, len(files)+1)
 
 	headers := []string{"Name", "Size", "Modified", "Perms"}
@@ -249,8 +276,8 @@ func printTable(files []FileInfo, now time.Time, config Config) {
 
 	for i, file := range files {
 		row := []string{
-			formatName(file),
-			formatSize(file.Size, file.IsDir),
+			formatName(file, config),
+			formatDirSize(file, config),
 			formatModified(file.ModTime, now),
 			formatPermissions(file.Mode),
 		}
@@ -260,8 +287,21 @@ func printTable(files []FileInfo, now time.Time, config Config) {
 		data[i+1] = row
 	}
 
+	renderFromData(data, config)
+}
+
+// renderFromData takes a pre-built table (first row is headers) and prints
+// it, shrinking columns as needed to fit the terminal. Shared by printTable
+// and the recursive flat-table renderer in tree.go.
+func renderFromData(data [][]string, config Config) {
+	if len(data) == 0 {
+		return
+	}
+
+	terminalWidth := max(getTerminalWidth(), 40)
+
 	displayWidths := calculateDisplayWidths(data)
-	mins, maxs := columnConstraints(config.ShowGit)
+	mins, maxs := columnConstraints(config.ShowGit, config.Icons)
 	for i := range displayWidths {
 		if i < len(mins) && mins[i] > 0 && displayWidths[i] < mins[i] {
 			displayWidths[i] = mins[i]
@@ -346,7 +386,7 @@ func calculateDisplayWidths(data [][]string) []int {
 	for i := range rows {
 		for j := range cols {
 			displayText := stripANSI(data[i][j])
-			width := utf8.RuneCountInString(displayText)
+			width := runewidth.StringWidth(displayText)
 			if width > widths[j] {
 				widths[j] = width
 			}
@@ -356,10 +396,16 @@ func calculateDisplayWidths(data [][]string) []int {
 	return widths
 }
 
-func columnConstraints(showGit bool) ([]int, []int) {
+func columnConstraints(showGit, showIcons bool) ([]int, []int) {
 	// Columns: Name, Size, Modified, Perms, (Git)
 	mins := []int{15, 6, 10, 10}
 	maxs := []int{50, 10, 15, 12}
+	if showIcons {
+		// Nerd-font glyphs render double-width in most terminals; leave room
+		// for "<glyph><space>" on top of the name itself.
+		mins[0] += 3
+		maxs[0] += 3
+	}
 	if showGit {
 		mins = append(mins, 6)
 		maxs = append(maxs, 12)
@@ -374,8 +420,52 @@ func lookupMin(mins []int, idx int, fallback int) int {
 	return fallback
 }
 
-func formatName(file FileInfo) string {
+// fileTypeOf classifies file into the coarse bucket icons/colors.toml
+// configs key off, used both for icon lookup and as the LS_COLORS fallback
+// when no extension-specific rule matches.
+func fileTypeOf(file FileInfo) icons.FileType {
+	switch {
+	case file.IsDir:
+		return icons.TypeDir
+	case file.Mode&fs.ModeSymlink != 0:
+		return icons.TypeSymlink
+	case file.Mode&fs.ModeSocket != 0:
+		return icons.TypeSocket
+	case file.Mode&fs.ModeNamedPipe != 0:
+		return icons.TypeFifo
+	case file.Mode&fs.ModeDevice != 0:
+		return icons.TypeDevice
+	case file.Mode.Perm()&0111 != 0:
+		return icons.TypeExecutable
+	default:
+		return icons.TypeRegular
+	}
+}
+
+func formatName(file FileInfo, config Config) string {
 	name := file.Name
+	ft := fileTypeOf(file)
+
+	colored := colorizeName(name, file, ft, config)
+
+	if config.Icons && config.IconMap != nil {
+		glyph := config.IconMap.Lookup(name, ft)
+		if glyph != "" {
+			return glyph + " " + colored
+		}
+	}
+
+	return colored
+}
+
+// colorizeName prefers an LS_COLORS/LSCOLORS-driven color when one is
+// configured, falling back to the built-in extension/type switch otherwise.
+func colorizeName(name string, file FileInfo, ft icons.FileType, config Config) string {
+	if config.ColorMap != nil {
+		if sgr := config.ColorMap.ColorFor(name, ft); sgr != "" {
+			return icons.Colorize(name, sgr)
+		}
+	}
 
 	if file.IsDir {
 		return color.New(color.FgBlue, color.Bold).Sprint(name)
@@ -407,6 +497,13 @@ func formatSize(size int64, isDir bool) string {
 		return color.New(color.FgCyan).Sprint("-")
 	}
 
+	return color.New(color.FgHiWhite).Sprint(humanSize(size))
+}
+
+// humanSize renders size as a human-readable "1.2 KB"-style string, with no
+// color applied, so callers can compose it into other colored cells (e.g.
+// the --dir-size "count / bytes" combined view).
+func humanSize(size int64) string {
 	const unit = 1024
 	if size < unit {
 		return fmt.Sprintf("%d B", size)
@@ -418,9 +515,7 @@ func formatSize(size int64, isDir bool) string {
 	}
 
 	units := []string{"KB", "MB", "GB", "TB"}
-	result := fmt.Sprintf("%.1f %s", float64(size)/float64(div), units[exp])
-
-	return color.New(color.FgHiWhite).Sprint(result)
+	return fmt.Sprintf("%.1f %s", float64(size)/float64(div), units[exp])
 }
 
 func formatModified(t time.Time, now time.Time) string {
@@ -563,6 +658,19 @@ func showColoredHelp(_ *cobra.Command) {
 		{"-t, --sort-modified", "sort by modified time (newest first)"},
 		{"-r, --reverse", "reverse sort order"},
 		{"-g, --git", "show git status inline"},
+		{"-R, --recursive", "recursively list subdirectories"},
+		{"-d, --depth N", "max recursion depth for -R (0 = unlimited)"},
+		{"--respect-gitignore", "skip .gitignore-matched paths when recursing (default true)"},
+		{"--tree", "render -R output as an indented tree"},
+		{"--after", "only show entries modified after this time"},
+		{"--before", "only show entries modified before this time"},
+		{"--top N", "keep only the N newest matching entries"},
+		{"--bottom N", "keep only the N oldest matching entries"},
+		{"-i, --icons", "show a nerd-font icon before each name"},
+		{"--no-cache", "don't read or write the on-disk git status cache"},
+		{"-o, --output", "output format: table, json, ndjson, tar"},
+		{"--dir-size", "show directory entry counts and/or sizes: count, bytes, both"},
+		{"-L, --dereference", "follow symlinked directories for --dir-size"},
 		{"-h, --help", "show this help message"},
 	}
 
@@ -580,6 +688,9 @@ func showColoredHelp(_ *cobra.Command) {
 		"lah -tr",
 		"lah -g",
 		"lah -tg",
+		"lah cache clear",
+		"lah -o json",
+		"lah --dir-size both",
 	}
 
 	for _, ex := range examples {
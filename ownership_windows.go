@@ -0,0 +1,8 @@
+//go:build windows
+
+package main
+
+// lookupOwnership is a no-op on Windows, which has no uid/gid concept.
+func lookupOwnership(path string) (uid, gid int, ok bool) {
+	return 0, 0, false
+}
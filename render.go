@@ -0,0 +1,281 @@
+package main
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+)
+
+// OutputFormat selects how a listing is rendered. The zero value behaves
+// like OutputTable so an unset Config.Output still renders the usual table.
+type OutputFormat string
+
+const (
+	OutputTable  OutputFormat = "table"
+	OutputJSON   OutputFormat = "json"
+	OutputNDJSON OutputFormat = "ndjson"
+	OutputTar    OutputFormat = "tar"
+)
+
+// IsTable reports whether format is (or defaults to) the table renderer.
+func (f OutputFormat) IsTable() bool {
+	return f == "" || f == OutputTable
+}
+
+func (f OutputFormat) String() string {
+	if f == "" {
+		return string(OutputTable)
+	}
+	return string(f)
+}
+
+// Set implements pflag.Value so OutputFormat can be bound directly to the
+// -o/--output flag with validation.
+func (f *OutputFormat) Set(value string) error {
+	parsed, err := parseOutputFormat(value)
+	if err != nil {
+		return err
+	}
+	*f = parsed
+	return nil
+}
+
+// Type implements pflag.Value.
+func (f OutputFormat) Type() string {
+	return "string"
+}
+
+// validOutputFormats is used both to validate -o/--output and to list the
+// accepted values in error messages.
+var validOutputFormats = []OutputFormat{OutputTable, OutputJSON, OutputNDJSON, OutputTar}
+
+func parseOutputFormat(value string) (OutputFormat, error) {
+	f := OutputFormat(value)
+	for _, valid := range validOutputFormats {
+		if f == valid {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("invalid --output %q (want one of: table, json, ndjson, tar)", value)
+}
+
+// Renderer turns a listing into output on stdout. TableRenderer is the
+// original box-drawn table; JSONRenderer/NDJSONRenderer/TarRenderer plug in
+// as structured alternatives via -o/--output.
+type Renderer interface {
+	Render(files []FileInfo, now time.Time, config Config) error
+}
+
+// TableRenderer is the original box-drawn table. It covers all three table
+// shapes lah can produce: the plain Name table (listDirectory), the flat
+// Path table for -R, and the indented --tree view — distinguishing them by
+// config.Recursive/config.Tree the same way the callers used to before
+// routing through the Renderer interface.
+type TableRenderer struct{}
+
+func (TableRenderer) Render(files []FileInfo, now time.Time, config Config) error {
+	if !config.Recursive {
+		printTable(files, now, config)
+		return nil
+	}
+	if config.Tree {
+		printTreeView(files, config.Root, config)
+		return nil
+	}
+	printFlatTreeTable(files, config.Root, now, config)
+	return nil
+}
+
+type JSONRenderer struct{}
+
+func (JSONRenderer) Render(files []FileInfo, now time.Time, config Config) error {
+	entries := make([]jsonEntry, len(files))
+	for i, f := range files {
+		entries[i] = toJSONEntry(f, config)
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+type NDJSONRenderer struct{}
+
+func (NDJSONRenderer) Render(files []FileInfo, now time.Time, config Config) error {
+	encoder := json.NewEncoder(os.Stdout)
+	for _, f := range files {
+		if err := encoder.Encode(toJSONEntry(f, config)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type TarRenderer struct{}
+
+func (TarRenderer) Render(files []FileInfo, now time.Time, config Config) error {
+	w := tar.NewWriter(os.Stdout)
+	defer w.Close()
+
+	for _, f := range files {
+		if err := writeTarEntry(w, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rendererFor resolves the Renderer for config.Output, defaulting to the
+// table renderer.
+func rendererFor(format OutputFormat) Renderer {
+	switch format {
+	case OutputJSON:
+		return JSONRenderer{}
+	case OutputNDJSON:
+		return NDJSONRenderer{}
+	case OutputTar:
+		return TarRenderer{}
+	default:
+		return TableRenderer{}
+	}
+}
+
+// renderStructured is the single entry point listDirectory/
+// listDirectoryRecursive use to print a listing, dispatching to the
+// Renderer for config.Output (color is disabled up front for non-table
+// formats by disableColorForOutput, called once from main).
+func renderStructured(files []FileInfo, now time.Time, config Config) error {
+	return rendererFor(config.Output).Render(files, now, config)
+}
+
+// disableColorForOutput suppresses color codes for non-table output modes,
+// and for table mode when stdout isn't a terminal.
+func disableColorForOutput(format OutputFormat) {
+	if !format.IsTable() {
+		color.NoColor = true
+		return
+	}
+	if !isatty.IsTerminal(os.Stdout.Fd()) && !isatty.IsCygwinTerminal(os.Stdout.Fd()) {
+		color.NoColor = true
+	}
+}
+
+// modeBits is the broken-out permission/special-bit view of a file's mode,
+// as exposed in JSON/NDJSON output.
+type modeBits struct {
+	Setuid bool   `json:"setuid"`
+	Setgid bool   `json:"setgid"`
+	Sticky bool   `json:"sticky"`
+	Perm   string `json:"perm"`
+}
+
+type jsonEntry struct {
+	Name      string   `json:"name"`
+	Path      string   `json:"path"`
+	Size      int64    `json:"size"`
+	Mode      string   `json:"mode"`
+	ModeBits  modeBits `json:"mode_bits"`
+	MTime     string   `json:"mtime"`
+	IsDir     bool     `json:"is_dir"`
+	IsHidden  bool     `json:"is_hidden"`
+	GitStatus string   `json:"git_status,omitempty"`
+}
+
+// unixPerm folds Go's setuid/setgid/sticky mode bits into the traditional
+// unix permission number (e.g. 4755 for a setuid executable).
+func unixPerm(mode fs.FileMode) uint32 {
+	perm := uint32(mode.Perm())
+	if mode&fs.ModeSetuid != 0 {
+		perm |= 04000
+	}
+	if mode&fs.ModeSetgid != 0 {
+		perm |= 02000
+	}
+	if mode&fs.ModeSticky != 0 {
+		perm |= 01000
+	}
+	return perm
+}
+
+func toJSONEntry(f FileInfo, config Config) jsonEntry {
+	entry := jsonEntry{
+		Name:     f.Name,
+		Path:     f.Path,
+		Size:     f.Size,
+		Mode:     fmt.Sprintf("%04o", unixPerm(f.Mode)),
+		MTime:    f.ModTime.Format(time.RFC3339Nano),
+		IsDir:    f.IsDir,
+		IsHidden: f.IsHidden,
+		ModeBits: modeBits{
+			Setuid: f.Mode&fs.ModeSetuid != 0,
+			Setgid: f.Mode&fs.ModeSetgid != 0,
+			Sticky: f.Mode&fs.ModeSticky != 0,
+			Perm:   fmt.Sprintf("%03o", f.Mode.Perm()),
+		},
+	}
+	if config.ShowGit {
+		entry.GitStatus = f.GitStatus
+	}
+	return entry
+}
+
+// writeTarEntry writes one file's header (and content, for regular files)
+// to w, resolving ownership and symlink targets from the filesystem the way
+// IPFS's UnixFS tar export restores mode/mtime/ownership on extraction.
+func writeTarEntry(w *tar.Writer, f FileInfo) error {
+	header := &tar.Header{
+		Name:    filepath.ToSlash(f.Path),
+		Mode:    int64(unixPerm(f.Mode)),
+		ModTime: f.ModTime,
+		Size:    f.Size,
+	}
+
+	if uid, gid, ok := lookupOwnership(f.Path); ok {
+		header.Uid = uid
+		header.Gid = gid
+	}
+
+	switch {
+	case f.Mode&fs.ModeSymlink != 0:
+		target, err := os.Readlink(f.Path)
+		if err != nil {
+			return err
+		}
+		header.Typeflag = tar.TypeSymlink
+		header.Linkname = target
+		header.Size = 0
+	case f.IsDir:
+		header.Typeflag = tar.TypeDir
+		header.Size = 0
+	default:
+		header.Typeflag = tar.TypeReg
+	}
+
+	if err := w.WriteHeader(header); err != nil {
+		return err
+	}
+
+	if header.Typeflag != tar.TypeReg {
+		return nil
+	}
+
+	file, err := os.Open(f.Path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(w, file)
+	return err
+}
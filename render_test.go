@@ -0,0 +1,54 @@
+package main
+
+import (
+	"io/fs"
+	"testing"
+	"time"
+)
+
+func TestParseOutputFormat(t *testing.T) {
+	for _, value := range []string{"table", "json", "ndjson", "tar"} {
+		if _, err := parseOutputFormat(value); err != nil {
+			t.Errorf("parseOutputFormat(%q) returned an error: %v", value, err)
+		}
+	}
+
+	if _, err := parseOutputFormat("yaml"); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
+
+func TestUnixPerm(t *testing.T) {
+	tests := []struct {
+		name string
+		mode fs.FileMode
+		want uint32
+	}{
+		{"regular", 0644, 0644},
+		{"setuid executable", 0755 | fs.ModeSetuid, 04755},
+		{"setgid executable", 0750 | fs.ModeSetgid, 02750},
+		{"sticky dir", 0777 | fs.ModeSticky | fs.ModeDir, 01777},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unixPerm(tt.mode); got != tt.want {
+				t.Errorf("unixPerm(%v) = %04o, want %04o", tt.mode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToJSONEntryOmitsGitStatusWhenDisabled(t *testing.T) {
+	f := FileInfo{Name: "main.go", Path: "main.go", ModTime: time.Now(), GitStatus: "+1 -0"}
+
+	entry := toJSONEntry(f, Config{ShowGit: false})
+	if entry.GitStatus != "" {
+		t.Errorf("expected GitStatus to be omitted when ShowGit is false, got %q", entry.GitStatus)
+	}
+
+	entry = toJSONEntry(f, Config{ShowGit: true})
+	if entry.GitStatus != "+1 -0" {
+		t.Errorf("expected GitStatus to be passed through when ShowGit is true, got %q", entry.GitStatus)
+	}
+}
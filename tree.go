@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+)
+
+// ignoreScope pairs a directory with the gitignore patterns that apply from
+// that directory downward, so a pattern defined in a subdirectory's
+// .gitignore never leaks out to its siblings or parent.
+type ignoreScope struct {
+	dir     string
+	matcher gitignore.Matcher
+}
+
+// loadGitignore reads the .gitignore file (if any) in dir and returns a
+// matcher scoped to dir, plus ok=false when the directory has no patterns.
+func loadGitignore(dir string) (gitignore.Matcher, bool) {
+	f, err := os.Open(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		return nil, false
+	}
+	defer f.Close()
+
+	var patterns []gitignore.Pattern
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, gitignore.ParsePattern(line, nil))
+	}
+
+	if len(patterns) == 0 {
+		return nil, false
+	}
+	return gitignore.NewMatcher(patterns), true
+}
+
+// isIgnored checks absPath (an entry's full path) against every scope whose
+// directory is an ancestor of it, most specific first, so a subdirectory's
+// .gitignore only ever matches paths under that subdirectory.
+func isIgnored(scopes []ignoreScope, absPath string, isDir bool) bool {
+	for i := len(scopes) - 1; i >= 0; i-- {
+		scope := scopes[i]
+		relToScope, err := filepath.Rel(scope.dir, absPath)
+		if err != nil {
+			continue
+		}
+		if relToScope == "." || strings.HasPrefix(relToScope, "..") {
+			continue
+		}
+		comps := strings.Split(filepath.ToSlash(relToScope), "/")
+		if scope.matcher.Match(comps, isDir) {
+			return true
+		}
+	}
+	return false
+}
+
+// walkTree recursively collects FileInfo entries under root in depth-first
+// order, honoring config.Depth (0 = unlimited) and, when
+// config.RespectGitignore is set, skipping paths matched by any .gitignore
+// found walking down from root. Siblings within each directory are sorted
+// (using the same rule as the flat listing) before recursing, so the
+// resulting order is already correct parent-before-children, sorted-among-
+// siblings tree order — callers must not re-sort the flat result afterward,
+// or they'll destroy that nesting.
+func walkTree(root string, config Config) ([]FileInfo, error) {
+	var files []FileInfo
+
+	var walk func(dir string, depth int, scopes []ignoreScope) error
+	walk = func(dir string, depth int, scopes []ignoreScope) error {
+		if config.RespectGitignore {
+			if matcher, ok := loadGitignore(dir); ok {
+				scopes = append(scopes, ignoreScope{dir: dir, matcher: matcher})
+			}
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+
+		var children []FileInfo
+		for _, entry := range entries {
+			name := entry.Name()
+			path := filepath.Join(dir, name)
+
+			if config.RespectGitignore && isIgnored(scopes, path, entry.IsDir()) {
+				continue
+			}
+
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+
+			fileInfo := FileInfo{
+				Name:     name,
+				Path:     path,
+				Size:     info.Size(),
+				Mode:     info.Mode(),
+				ModTime:  info.ModTime(),
+				IsDir:    entry.IsDir(),
+				IsHidden: strings.HasPrefix(name, "."),
+			}
+			if config.ShowGit {
+				fileInfo.GitStatus = getGitStatusCached(fileInfo, config.DiskCache, config.GitCache)
+			}
+			children = append(children, fileInfo)
+		}
+
+		sortFiles(children, config)
+
+		for _, fileInfo := range children {
+			files = append(files, fileInfo)
+
+			if fileInfo.IsDir && (config.Depth == 0 || depth < config.Depth) {
+				if err := walk(fileInfo.Path, depth+1, scopes); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+
+	if err := walk(root, 1, nil); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// listDirectoryRecursive is the -R/--recursive counterpart to listDirectory:
+// it walks the whole subtree under path and renders it either as one flat
+// table (Path column) or as an indented tree, depending on config.Tree.
+func listDirectoryRecursive(path string, config Config) error {
+	config.GitCache, config.DiskCache = prepareGitCaches(config)
+	if config.DiskCache != nil {
+		defer config.DiskCache.Close()
+	}
+
+	files, err := walkTree(path, config)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	files, hidden, err := applyFilters(files, config, now)
+	if err != nil {
+		return err
+	}
+
+	computeDirSizes(files, config)
+
+	config.Root = path
+	if err := renderStructured(files, now, config); err != nil {
+		return err
+	}
+	if config.Output.IsTable() {
+		printFilterSummary(hidden)
+	}
+	return nil
+}
+
+// relativeToRoot returns file.Path relative to root for display purposes,
+// falling back to the original path if it isn't actually under root.
+func relativeToRoot(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return path
+	}
+	return rel
+}
+
+// printFlatTreeTable renders a recursive listing as a single table with a
+// Path column showing each entry's location relative to root.
+func printFlatTreeTable(files []FileInfo, root string, now time.Time, config Config) {
+	if len(files) == 0 {
+		return
+	}
+
+	data := make([][]string, len(files)+1)
+	headers := []string{"Path", "Size", "Modified", "Perms"}
+	if config.ShowGit {
+		headers = append(headers, "Git")
+	}
+	data[0] = headers
+
+	for i, file := range files {
+		row := []string{
+			formatPathName(file, root, config),
+			formatDirSize(file, config),
+			formatModified(file.ModTime, now),
+			formatPermissions(file.Mode),
+		}
+		if config.ShowGit {
+			row = append(row, formatGitStatus(file.GitStatus))
+		}
+		data[i+1] = row
+	}
+
+	renderFromData(data, config)
+}
+
+// printTreeView renders a recursive listing as an indented tree, one line
+// per entry, nested under its parent directory.
+func printTreeView(files []FileInfo, root string, config Config) {
+	for _, file := range files {
+		rel := relativeToRoot(root, file.Path)
+		depth := strings.Count(filepath.ToSlash(rel), "/")
+		indent := strings.Repeat("  ", depth)
+		fmt.Printf("%s%s\n", indent, formatName(file, config))
+	}
+}
+
+// formatPathName renders an entry's path relative to root (instead of its
+// bare name) using the same color rules as formatName.
+func formatPathName(file FileInfo, root string, config Config) string {
+	colored := formatName(file, config)
+	rel := relativeToRoot(root, file.Path)
+	dir := filepath.Dir(rel)
+	if dir == "." {
+		return colored
+	}
+	return color.New(color.FgHiBlack).Sprint(dir+string(filepath.Separator)) + colored
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWalkTreeRespectsPerDirectoryGitignore(t *testing.T) {
+	root := t.TempDir()
+
+	mustWrite := func(rel, content string) {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("MkdirAll(%q): %v", rel, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("WriteFile(%q): %v", rel, err)
+		}
+	}
+
+	mustWrite(".gitignore", "*.log\n")
+	mustWrite("root.log", "")
+	mustWrite("keep.txt", "")
+	mustWrite("sub/.gitignore", "keep.txt\n")
+	mustWrite("sub/keep.txt", "")
+	mustWrite("sub/other.txt", "")
+
+	files, err := walkTree(root, Config{RespectGitignore: true})
+	if err != nil {
+		t.Fatalf("walkTree: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, f := range files {
+		seen[relativeToRoot(root, f.Path)] = true
+	}
+
+	if seen["root.log"] {
+		t.Error("root.log should be ignored by the root .gitignore")
+	}
+	if !seen["keep.txt"] {
+		t.Error("keep.txt at root should not be ignored (sub/.gitignore doesn't apply to it)")
+	}
+	if seen[filepath.Join("sub", "keep.txt")] {
+		t.Error("sub/keep.txt should be ignored by sub/.gitignore")
+	}
+	if !seen[filepath.Join("sub", "other.txt")] {
+		t.Error("sub/other.txt should not be ignored")
+	}
+}
+
+func TestWalkTreeDepthLimit(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "a", "b", "c")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(nested, "deep.txt"), nil, 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	files, err := walkTree(root, Config{Depth: 1})
+	if err != nil {
+		t.Fatalf("walkTree: %v", err)
+	}
+
+	for _, f := range files {
+		rel := relativeToRoot(root, f.Path)
+		if rel == filepath.Join("a", "b") || rel == filepath.Join("a", "b", "c") {
+			t.Errorf("depth-limited walk should not have descended into %q", rel)
+		}
+	}
+}